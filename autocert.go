@@ -0,0 +1,69 @@
+package baa
+
+import (
+	"crypto/tls"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// autocertManager returns b.AutoTLSManager, creating it with sane
+// defaults the first time it is needed.
+func (b *Baa) autocertManager(hosts ...string) *autocert.Manager {
+	if b.AutoTLSManager == nil {
+		b.AutoTLSManager = &autocert.Manager{
+			Prompt: autocert.AcceptTOS,
+			Cache:  autocert.DirCache(defaultAutocertCacheDir()),
+		}
+	}
+	if len(hosts) > 0 {
+		b.AutoTLSManager.HostPolicy = autocert.HostWhitelist(hosts...)
+	}
+	return b.AutoTLSManager
+}
+
+func defaultAutocertCacheDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".baa", "autocert")
+	}
+	return filepath.Join(home, ".baa", "autocert")
+}
+
+// RunAutoTLS starts an HTTPS server on addr with certificates issued and
+// renewed automatically by Let's Encrypt for hosts. It registers a Pre
+// middleware that answers ACME HTTP-01 challenges, so the plain HTTP
+// listener that fronts the same hosts (commonly port 80, redirecting to
+// HTTPS) must also be served by this Baa instance. This matches what
+// StartAutoTLS in comparable frameworks provides, without requiring
+// callers to wire up golang.org/x/crypto/acme/autocert themselves.
+func (b *Baa) RunAutoTLS(addr string, hosts ...string) {
+	if err := b.StartAutoTLS(addr, hosts...); err != nil {
+		b.Logger().Fatal(err)
+	}
+}
+
+// StartAutoTLS is like RunAutoTLS but returns the error instead of
+// exiting the process.
+func (b *Baa) StartAutoTLS(addr string, hosts ...string) error {
+	m := b.autocertManager(hosts...)
+	b.Pre(acmeChallengeHandler(m))
+	s := b.Server(addr)
+	s.TLSConfig = &tls.Config{GetCertificate: m.GetCertificate}
+	return b.start(s, "", "")
+}
+
+// acmeChallengeHandler answers ACME HTTP-01 challenges for m and falls
+// through to the rest of the chain for every other request.
+func acmeChallengeHandler(m *autocert.Manager) HandlerFuncE {
+	h := m.HTTPHandler(nil)
+	return func(c *Context) error {
+		if !strings.HasPrefix(c.Req.URL.Path, "/.well-known/acme-challenge/") {
+			return c.Next()
+		}
+		h.ServeHTTP(c.Resp, c.Req)
+		return nil
+	}
+}
@@ -1,12 +1,15 @@
 package baa
 
 import (
+	"context"
 	"errors"
 	"log"
 	"net/http"
 	"os"
 	"strings"
 	"sync"
+
+	"golang.org/x/crypto/acme/autocert"
 )
 
 const (
@@ -23,14 +26,26 @@ var Env string
 
 // Baa provlider an application
 type Baa struct {
-	debug           bool
-	name            string
-	di              *DI
-	router          *Router
-	pool            sync.Pool
-	errorHandler    ErrorHandleFunc
-	notFoundHandler HandlerFunc
-	middleware      []HandlerFunc
+	debug            bool
+	name             string
+	di               *DI
+	router           Router
+	pool             sync.Pool
+	errorHandler     ErrorHandleFunc
+	notFoundHandler  HandlerFunc
+	middleware       []HandlerFuncE
+	pre              []HandlerFuncE
+	mounts           []*mount
+	server           *http.Server
+	onStartHooks     []func() error
+	onShutdownHooks  []func(context.Context) error
+	activeMu         sync.Mutex
+	active           map[*Context]struct{}
+	// AutoTLSManager manages certificates for StartAutoTLS/RunAutoTLS. It
+	// is created lazily with sane defaults (~/.baa/autocert cache dir,
+	// auto-accepted ToS) the first time one of those is called; set it
+	// beforehand to customize the cache or host policy.
+	AutoTLSManager *autocert.Manager
 }
 
 // Middleware middleware handler
@@ -42,6 +57,13 @@ type Handler interface{}
 // HandlerFunc context handler func
 type HandlerFunc func(*Context)
 
+// HandlerFuncE is a context handler that can fail. A non-nil error stops
+// the middleware chain and is routed to the application's ErrorHandleFunc
+// once, after the chain unwinds. Route registration (Get, Post, Group.Use,
+// ...) accepts both HandlerFunc and HandlerFuncE, adapting the former
+// with a nil-returning shim; see toHandlerFuncE.
+type HandlerFuncE func(*Context) error
+
 // ErrorHandleFunc HTTP error handleFunc
 type ErrorHandleFunc func(error, *Context)
 
@@ -54,7 +76,7 @@ const defaultAppName = "_default_"
 // New create a baa application without any config.
 func New() *Baa {
 	b := new(Baa)
-	b.middleware = make([]HandlerFunc, 0)
+	b.middleware = make([]HandlerFuncE, 0)
 	b.pool = sync.Pool{
 		New: func() interface{} {
 			return newContext(nil, nil, b)
@@ -65,9 +87,11 @@ func New() *Baa {
 	}
 	b.di = newDI()
 	b.router = newRouter()
+	b.active = make(map[*Context]struct{})
 	b.notFoundHandler = b.DefaultNotFoundHandler
 	b.SetDI("logger", log.New(os.Stderr, "[Baa] ", log.LstdFlags))
 	b.SetDI("render", newRender())
+	b.SetDI("binder", newBinder())
 	return b
 }
 
@@ -115,6 +139,7 @@ func (b *Baa) RunTLSServer(s *http.Server, crtFile, keyFile string) {
 }
 
 func (b *Baa) run(s *http.Server, files ...string) {
+	b.server = s
 	s.Handler = b
 	b.Logger().Printf("Run mode: %s", Env)
 	if len(files) == 0 {
@@ -128,22 +153,131 @@ func (b *Baa) run(s *http.Server, files ...string) {
 	}
 }
 
+// OnStart registers a hook run by Start, StartTLS and StartAutoTLS right
+// before the server starts accepting connections. Hooks run in
+// registration order; the first error aborts startup and is returned.
+// Run, RunTLS and the other Run* methods do not run these hooks.
+func (b *Baa) OnStart(h func() error) {
+	b.onStartHooks = append(b.onStartHooks, h)
+}
+
+// OnShutdown registers a hook run during Shutdown, after the server has
+// stopped accepting new connections but the process is still alive -
+// useful for closing database pools or flushing buffers.
+func (b *Baa) OnShutdown(h func(context.Context) error) {
+	b.onShutdownHooks = append(b.onShutdownHooks, h)
+}
+
+// Start starts an HTTP server on addr and blocks until it stops, for
+// example because Shutdown was called. Unlike Run, it returns the error
+// instead of exiting the process, so callers can participate in
+// supervised process lifecycles (systemd, k8s preStop, etc).
+func (b *Baa) Start(addr string) error {
+	return b.start(b.Server(addr))
+}
+
+// StartTLS is like Start but serves HTTPS using certfile and keyfile.
+func (b *Baa) StartTLS(addr, certfile, keyfile string) error {
+	return b.start(b.Server(addr), certfile, keyfile)
+}
+
+func (b *Baa) start(s *http.Server, files ...string) error {
+	b.server = s
+	s.Handler = b
+	for _, h := range b.onStartHooks {
+		if err := h(); err != nil {
+			return err
+		}
+	}
+	b.Logger().Printf("Run mode: %s", Env)
+	var err error
+	if len(files) == 0 {
+		b.Logger().Printf("Listen %s", s.Addr)
+		err = s.ListenAndServe()
+	} else if len(files) == 2 {
+		b.Logger().Printf("Listen %s with TLS", s.Addr)
+		err = s.ListenAndServeTLS(files[0], files[1])
+	} else {
+		return errors.New("baa: invalid TLS configuration")
+	}
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return err
+}
+
+// Shutdown gracefully stops the server started by Start/StartTLS: it
+// cancels every in-flight request's (*Context).Context(), so handlers
+// selecting on its Done() channel can wind down early, then stops
+// accepting new connections and waits for in-flight requests to finish,
+// bounded by ctx, then runs the registered OnShutdown hooks. A server
+// started by Run/RunTLS hands the error its listener returns on Shutdown
+// to Logger().Fatal, which with the default logger exits the process as
+// soon as the listener closes.
+func (b *Baa) Shutdown(ctx context.Context) error {
+	if b.server == nil {
+		return nil
+	}
+	b.activeMu.Lock()
+	for c := range b.active {
+		c.cancel()
+	}
+	b.activeMu.Unlock()
+	if err := b.server.Shutdown(ctx); err != nil {
+		return err
+	}
+	for _, h := range b.onShutdownHooks {
+		if err := h(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (b *Baa) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	c := b.pool.Get().(*Context)
 	c.reset(w, r)
 
+	// register c as in-flight so Shutdown can cancel its Context()
+	b.activeMu.Lock()
+	b.active[c] = struct{}{}
+	b.activeMu.Unlock()
+	defer func() {
+		b.activeMu.Lock()
+		delete(b.active, c)
+		b.activeMu.Unlock()
+		b.pool.Put(c)
+	}()
+
+	// pre middleware runs ahead of routing, e.g. to answer ACME HTTP-01
+	// challenges or rewrite the path before it is matched
+	c.handlers = append(c.handlers, b.pre...)
+
+	// middleware registered via Use runs for every request, mounted or not
+	c.handlers = append(c.handlers, b.middleware...)
+
+	// dispatch into a mounted sub-application, if any, after our own
+	// middleware has run but before routing against our own router
+	if m, rel := b.matchMount(r.URL.Path); m != nil {
+		c.handlers = append(c.handlers, mountHandler(m, rel))
+		if err := c.Next(); err != nil {
+			b.Error(err, c)
+		}
+		return
+	}
+
 	// build handler chain
-	route := b.router.match(r.Method, r.URL.Path, c)
+	route := b.router.Match(r.Method, r.URL.Path, c)
 	// notFound
 	if route == nil || route.handlers == nil {
-		c.handlers = append(c.handlers, b.notFoundHandler)
+		c.handlers = append(c.handlers, toHandlerFuncE(b.notFoundHandler))
 	} else {
 		c.handlers = append(c.handlers, route.handlers...)
 	}
 
-	c.Next()
-
-	b.pool.Put(c)
+	if err := c.Next(); err != nil {
+		b.Error(err, c)
+	}
 }
 
 // SetDebug set baa debug
@@ -175,6 +309,18 @@ func (b *Baa) Use(m ...Middleware) {
 	}
 }
 
+// Pre registers middleware that runs before routing, for every request,
+// even ones that match no route. It is mainly useful for things that need
+// to act on the raw path/headers ahead of the router, such as answering
+// ACME HTTP-01 challenges for StartAutoTLS.
+func (b *Baa) Pre(m ...Middleware) {
+	for i := range m {
+		if m[i] != nil {
+			b.pre = append(b.pre, wrapMiddleware(m[i]))
+		}
+	}
+}
+
 // SetDI registers a dependency injection
 func (b *Baa) SetDI(name string, h interface{}) {
 	b.di.set(name, h)
@@ -202,83 +348,87 @@ func (b *Baa) Static(prefix string, dir string, index bool, h HandlerFunc) {
 // SetAutoHead sets the value who determines whether add HEAD method automatically
 // when GET method is added. Combo router will not be affected by this value.
 func (b *Baa) SetAutoHead(v bool) {
-	b.router.autoHead = v
+	b.router.SetAutoHead(v)
 }
 
 // SetAutoTrailingSlash optional trailing slash.
 func (b *Baa) SetAutoTrailingSlash(v bool) {
-	b.router.autoTrailingSlash = v
+	b.router.SetAutoTrailingSlash(v)
+}
+
+// SetRouter swaps the router implementation, e.g. to use the radix-tree
+// router for large route tables. It must be called before any routes are
+// registered.
+func (b *Baa) SetRouter(r Router) {
+	b.router = r
 }
 
 // Route is a shortcut for same handlers but different HTTP methods.
 //
 // Example:
 // 		baa.Route("/", "GET,POST", h)
-func (b *Baa) Route(pattern, methods string, h ...HandlerFunc) *Route {
+func (b *Baa) Route(pattern, methods string, h ...Handler) *Route {
 	var ru *Route
 	var ms []string
 	if methods == "*" {
-		ms = b.router.methods()
+		ms = b.router.Methods()
 	} else {
 		ms = strings.Split(methods, ",")
 	}
+	hs := toHandlerFuncEs(h)
 	for _, m := range ms {
-		ru = b.router.handle(strings.TrimSpace(m), pattern, h)
+		ru = b.router.Add(strings.TrimSpace(m), pattern, hs)
 	}
 	return ru
 }
 
-// Group registers a list of same prefix route
-func (b *Baa) Group(pattern string, f func(), h ...HandlerFunc) {
-	b.router.groupAdd(pattern, f, h)
-}
-
-// Any is a shortcut for b.router.handle("*", pattern, handlers)
-func (b *Baa) Any(pattern string, h ...HandlerFunc) *Route {
+// Any is a shortcut for b.router.Add("*", pattern, handlers)
+func (b *Baa) Any(pattern string, h ...Handler) *Route {
 	var ru *Route
-	for _, m := range b.router.methods() {
-		ru = b.router.handle(m, pattern, h)
+	hs := toHandlerFuncEs(h)
+	for _, m := range b.router.Methods() {
+		ru = b.router.Add(m, pattern, hs)
 	}
 	return ru
 }
 
-// Delete is a shortcut for b.router.handle("DELETE", pattern, handlers)
-func (b *Baa) Delete(pattern string, h ...HandlerFunc) *Route {
-	return b.router.handle("DELETE", pattern, h)
+// Delete is a shortcut for b.router.Add("DELETE", pattern, handlers)
+func (b *Baa) Delete(pattern string, h ...Handler) *Route {
+	return b.router.Add("DELETE", pattern, toHandlerFuncEs(h))
 }
 
-// Get is a shortcut for b.router.handle("GET", pattern, handlers)
-func (b *Baa) Get(pattern string, h ...HandlerFunc) *Route {
-	rs := b.router.handle("GET", pattern, h)
-	if b.router.autoHead {
+// Get is a shortcut for b.router.Add("GET", pattern, handlers)
+func (b *Baa) Get(pattern string, h ...Handler) *Route {
+	rs := b.router.Add("GET", pattern, toHandlerFuncEs(h))
+	if b.router.AutoHead() {
 		b.Head(pattern, h...)
 	}
 	return rs
 }
 
-// Head is a shortcut for b.router.handle("HEAD", pattern, handlers)
-func (b *Baa) Head(pattern string, h ...HandlerFunc) *Route {
-	return b.router.handle("HEAD", pattern, h)
+// Head is a shortcut for b.router.Add("HEAD", pattern, handlers)
+func (b *Baa) Head(pattern string, h ...Handler) *Route {
+	return b.router.Add("HEAD", pattern, toHandlerFuncEs(h))
 }
 
-// Options is a shortcut for b.router.handle("OPTIONS", pattern, handlers)
-func (b *Baa) Options(pattern string, h ...HandlerFunc) *Route {
-	return b.router.handle("OPTIONS", pattern, h)
+// Options is a shortcut for b.router.Add("OPTIONS", pattern, handlers)
+func (b *Baa) Options(pattern string, h ...Handler) *Route {
+	return b.router.Add("OPTIONS", pattern, toHandlerFuncEs(h))
 }
 
-// Patch is a shortcut for b.router.handle("PATCH", pattern, handlers)
-func (b *Baa) Patch(pattern string, h ...HandlerFunc) *Route {
-	return b.router.handle("PATCH", pattern, h)
+// Patch is a shortcut for b.router.Add("PATCH", pattern, handlers)
+func (b *Baa) Patch(pattern string, h ...Handler) *Route {
+	return b.router.Add("PATCH", pattern, toHandlerFuncEs(h))
 }
 
-// Post is a shortcut for b.router.handle("POST", pattern, handlers)
-func (b *Baa) Post(pattern string, h ...HandlerFunc) *Route {
-	return b.router.handle("POST", pattern, h)
+// Post is a shortcut for b.router.Add("POST", pattern, handlers)
+func (b *Baa) Post(pattern string, h ...Handler) *Route {
+	return b.router.Add("POST", pattern, toHandlerFuncEs(h))
 }
 
-// Put is a shortcut for b.router.handle("PUT", pattern, handlers)
-func (b *Baa) Put(pattern string, h ...HandlerFunc) *Route {
-	return b.router.handle("PUT", pattern, h)
+// Put is a shortcut for b.router.Add("PUT", pattern, handlers)
+func (b *Baa) Put(pattern string, h ...Handler) *Route {
+	return b.router.Add("PUT", pattern, toHandlerFuncEs(h))
 }
 
 // SetNotFound set not found route handler
@@ -326,24 +476,31 @@ func (b *Baa) DefaultNotFoundHandler(c *Context) {
 
 // URLFor use named route return format url
 func (b *Baa) URLFor(name string, args ...interface{}) string {
-	return b.router.urlFor(name, args...)
+	return b.router.URLFor(name, args...)
 }
 
-// wrapMiddleware wraps middleware.
-func wrapMiddleware(m Middleware) HandlerFunc {
+// wrapMiddleware wraps middleware as a HandlerFuncE, so it can take its
+// place in a handler chain alongside route handlers.
+func wrapMiddleware(m Middleware) HandlerFuncE {
 	switch m := m.(type) {
-	case HandlerFunc:
+	case HandlerFuncE:
 		return m
-	case func(*Context):
+	case func(*Context) error:
 		return m
+	case HandlerFunc:
+		return toHandlerFuncE(m)
+	case func(*Context):
+		return toHandlerFuncE(HandlerFunc(m))
 	case http.Handler, http.HandlerFunc:
-		return wrapHandlerFunc(func(c *Context) {
+		return func(c *Context) error {
 			m.(http.Handler).ServeHTTP(c.Resp, c.Req)
-		})
+			return nil
+		}
 	case func(http.ResponseWriter, *http.Request):
-		return wrapHandlerFunc(func(c *Context) {
+		return func(c *Context) error {
 			m(c.Resp, c.Req)
-		})
+			return nil
+		}
 	default:
 		panic("unknown middleware")
 	}
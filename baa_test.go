@@ -0,0 +1,26 @@
+package baa
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+// TestUseMiddlewareRunsForPlainRoute verifies middleware registered via Use
+// is spliced into the handler chain for an ordinary (non-group, non-mount)
+// route, not just for requests dispatched into a mounted sub-application.
+func TestUseMiddlewareRunsForPlainRoute(t *testing.T) {
+	app := New()
+	var trail []string
+	app.Use(HandlerFunc(func(c *Context) {
+		trail = append(trail, "mw")
+		c.Next()
+	}))
+	app.Get("/ping", func(c *Context) {
+		trail = append(trail, "handler")
+	})
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/ping", nil))
+	if !equalTrail(trail, []string{"mw", "handler"}) {
+		t.Errorf("trail = %v, want [mw handler]", trail)
+	}
+}
@@ -0,0 +1,168 @@
+package baa
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"mime"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+)
+
+// Binder binds request data onto i, which is typically a pointer to a
+// struct. Register a custom implementation with b.SetDI("binder", ...) to
+// replace the default dispatch-by-Content-Type behaviour.
+type Binder interface {
+	Bind(i interface{}, c *Context) error
+}
+
+// defaultBinder is the Binder registered by New(). It dispatches on the
+// request's Content-Type: application/json and application/xml decode
+// the body and form content types decode into "form"-tagged fields.
+// Query parameters ("query"-tagged fields) and matched route parameters
+// ("param"-tagged fields) are always bound, regardless of method.
+type defaultBinder struct{}
+
+func newBinder() Binder {
+	return &defaultBinder{}
+}
+
+// Bind binds the current request onto v using the Binder registered on
+// c's application (the default one if none was set via SetDI("binder",
+// ...)).
+func (c *Context) Bind(v interface{}) error {
+	b, _ := c.baa.GetDI("binder").(Binder)
+	if b == nil {
+		b = newBinder()
+	}
+	return b.Bind(v, c)
+}
+
+func (*defaultBinder) Bind(i interface{}, c *Context) error {
+	req := c.Req
+	hasBody := req.Method != http.MethodGet && req.Method != http.MethodHead && req.Method != http.MethodDelete &&
+		req.Header.Get("Content-Type") != ""
+	if hasBody {
+		ctype, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+		switch ctype {
+		case "application/json":
+			if err := json.NewDecoder(req.Body).Decode(i); err != nil {
+				return err
+			}
+		case "application/xml", "text/xml":
+			if err := xml.NewDecoder(req.Body).Decode(i); err != nil {
+				return err
+			}
+		case "application/x-www-form-urlencoded":
+			if err := req.ParseForm(); err != nil {
+				return err
+			}
+			if err := bindTag(i, req.Form, "form"); err != nil {
+				return err
+			}
+		case "multipart/form-data":
+			if err := req.ParseMultipartForm(32 << 20); err != nil {
+				return err
+			}
+			if err := bindTag(i, req.Form, "form"); err != nil {
+				return err
+			}
+		default:
+			return errors.New("baa: bind: unsupported content type " + ctype)
+		}
+	}
+	if err := bindTag(i, req.URL.Query(), "query"); err != nil {
+		return err
+	}
+	return bindParams(i, c)
+}
+
+// bindParams binds c's matched route parameters onto "param"-tagged
+// fields of i.
+func bindParams(i interface{}, c *Context) error {
+	v, err := bindTarget(i)
+	if err != nil {
+		return err
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := t.Field(i).Tag.Get("param")
+		if name == "" {
+			continue
+		}
+		if val := c.Param(name); val != "" {
+			if err := setField(v.Field(i), val); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// bindTag binds values onto i's fields tagged tag:"name".
+func bindTag(i interface{}, data url.Values, tag string) error {
+	v, err := bindTarget(i)
+	if err != nil {
+		return err
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := t.Field(i).Tag.Get(tag)
+		if name == "" {
+			continue
+		}
+		if val := data.Get(name); val != "" {
+			if err := setField(v.Field(i), val); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func bindTarget(i interface{}) (reflect.Value, error) {
+	v := reflect.ValueOf(i)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, errors.New("baa: bind target must be a non-nil pointer to a struct")
+	}
+	return v.Elem(), nil
+}
+
+func setField(field reflect.Value, val string) error {
+	if !field.CanSet() {
+		return nil
+	}
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(val)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(val, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(n)
+	case reflect.Bool:
+		n, err := strconv.ParseBool(val)
+		if err != nil {
+			return err
+		}
+		field.SetBool(n)
+	default:
+		return errors.New("baa: bind: unsupported field kind " + field.Kind().String())
+	}
+	return nil
+}
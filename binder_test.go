@@ -0,0 +1,100 @@
+package baa
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type bindFixture struct {
+	Name  string `form:"name" query:"name"`
+	Age   int    `form:"age" query:"age"`
+	ID    string `param:"id"`
+	Title string `json:"title" xml:"title"`
+}
+
+func TestBindJSON(t *testing.T) {
+	app := New()
+	req := httptest.NewRequest("POST", "/", strings.NewReader(`{"title":"hello"}`))
+	req.Header.Set("Content-Type", "application/json")
+	c := newContext(httptest.NewRecorder(), req, app)
+
+	var v bindFixture
+	if err := c.Bind(&v); err != nil {
+		t.Fatalf("Bind: %v", err)
+	}
+	if v.Title != "hello" {
+		t.Errorf("Title = %q, want %q", v.Title, "hello")
+	}
+}
+
+func TestBindForm(t *testing.T) {
+	app := New()
+	req := httptest.NewRequest("POST", "/", strings.NewReader("name=gopher&age=9"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	c := newContext(httptest.NewRecorder(), req, app)
+
+	var v bindFixture
+	if err := c.Bind(&v); err != nil {
+		t.Fatalf("Bind: %v", err)
+	}
+	if v.Name != "gopher" || v.Age != 9 {
+		t.Errorf("got %+v, want Name=gopher Age=9", v)
+	}
+}
+
+func TestBindQuery(t *testing.T) {
+	app := New()
+	req := httptest.NewRequest("GET", "/?name=gopher&age=9", nil)
+	c := newContext(httptest.NewRecorder(), req, app)
+
+	var v bindFixture
+	if err := c.Bind(&v); err != nil {
+		t.Fatalf("Bind: %v", err)
+	}
+	if v.Name != "gopher" || v.Age != 9 {
+		t.Errorf("got %+v, want Name=gopher Age=9", v)
+	}
+}
+
+func TestBindParam(t *testing.T) {
+	app := New()
+	req := httptest.NewRequest("GET", "/users/42", nil)
+	c := newContext(httptest.NewRecorder(), req, app)
+	c.SetParam("id", "42")
+
+	var v bindFixture
+	if err := c.Bind(&v); err != nil {
+		t.Fatalf("Bind: %v", err)
+	}
+	if v.ID != "42" {
+		t.Errorf("ID = %q, want %q", v.ID, "42")
+	}
+}
+
+func TestBindParamNoContentType(t *testing.T) {
+	app := New()
+	req := httptest.NewRequest("POST", "/users/42/activate", nil)
+	c := newContext(httptest.NewRecorder(), req, app)
+	c.SetParam("id", "42")
+
+	var v bindFixture
+	if err := c.Bind(&v); err != nil {
+		t.Fatalf("Bind: %v", err)
+	}
+	if v.ID != "42" {
+		t.Errorf("ID = %q, want %q", v.ID, "42")
+	}
+}
+
+func TestBindUnsupportedContentType(t *testing.T) {
+	app := New()
+	req := httptest.NewRequest("POST", "/", strings.NewReader("whatever"))
+	req.Header.Set("Content-Type", "application/protobuf")
+	c := newContext(httptest.NewRecorder(), req, app)
+
+	var v bindFixture
+	if err := c.Bind(&v); err == nil {
+		t.Fatal("Bind: expected error for unsupported content type, got nil")
+	}
+}
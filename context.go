@@ -0,0 +1,194 @@
+package baa
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+)
+
+// Response wraps http.ResponseWriter, recording the status code and byte
+// count written so middleware (and the access logger) can report on them.
+type Response struct {
+	http.ResponseWriter
+	status    int
+	size      int
+	committed bool
+}
+
+func newResponse(w http.ResponseWriter) *Response {
+	return &Response{ResponseWriter: w}
+}
+
+func (r *Response) reset(w http.ResponseWriter) {
+	r.ResponseWriter = w
+	r.status = http.StatusOK
+	r.size = 0
+	r.committed = false
+}
+
+// WriteHeader sends an HTTP response header with the provided status
+// code, ignoring repeated calls after the first.
+func (r *Response) WriteHeader(code int) {
+	if r.committed {
+		return
+	}
+	r.status = code
+	r.committed = true
+	r.ResponseWriter.WriteHeader(code)
+}
+
+// Write writes b to the connection, implicitly sending a 200 header if
+// none has been written yet.
+func (r *Response) Write(b []byte) (int, error) {
+	if !r.committed {
+		r.WriteHeader(http.StatusOK)
+	}
+	n, err := r.ResponseWriter.Write(b)
+	r.size += n
+	return n, err
+}
+
+// Status returns the HTTP status code that was sent.
+func (r *Response) Status() int {
+	return r.status
+}
+
+// Size returns the number of bytes written to the response body.
+func (r *Response) Size() int {
+	return r.size
+}
+
+// Hijack implements http.Hijacker so handlers can take over the
+// connection (e.g. for websockets).
+func (r *Response) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hj, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("baa: response does not support hijacking")
+	}
+	return hj.Hijack()
+}
+
+// Context carries request-scoped state: the request and response, the
+// matched route's handler chain, and whatever data handlers stash on it.
+// A Context is reused across requests via Baa's sync.Pool, so handlers
+// must not retain one past the request it was given for.
+type Context struct {
+	Resp       *Response
+	Req        *http.Request
+	baa        *Baa
+	store      map[string]interface{}
+	params     Params
+	paramCache [8]Param
+	handlers   []HandlerFuncE
+	hi         int
+	nextErr    error
+	ctx        context.Context
+	cancel     context.CancelFunc
+}
+
+// Param is a single matched route parameter, e.g. {Key: "id", Value:
+// "42"} for a route registered as "/user/:id".
+type Param struct {
+	Key   string
+	Value string
+}
+
+// Params is the set of route parameters matched for a request.
+type Params []Param
+
+func newContext(w http.ResponseWriter, r *http.Request, b *Baa) *Context {
+	c := &Context{
+		baa:  b,
+		Resp: newResponse(w),
+	}
+	c.reset(w, r)
+	return c
+}
+
+// reset reinitializes c for a new request so it can be served from the
+// pool; only the request-scoped context.Context (see Context) is created
+// anew for each request. params reuses the backing array in paramCache,
+// so matching a request's route parameters does not allocate either, as
+// long as a route has at most len(paramCache) parameters.
+func (c *Context) reset(w http.ResponseWriter, r *http.Request) {
+	c.Req = r
+	c.Resp.reset(w)
+	c.store = nil
+	c.params = c.paramCache[:0]
+	c.handlers = c.handlers[:0]
+	c.hi = 0
+	c.nextErr = nil
+	if c.cancel != nil {
+		c.cancel()
+	}
+	c.ctx, c.cancel = nil, nil
+	if r != nil {
+		c.ctx, c.cancel = context.WithCancel(r.Context())
+	}
+}
+
+// Next calls the next handler in the chain and returns its error, if any.
+// Middleware that wraps the rest of the chain (recovery, logging,
+// transactions, ...) calls Next itself and acts on the returned error;
+// the application's ErrorHandleFunc still only runs once, centrally,
+// after the whole chain has unwound.
+//
+// The error is also stashed on c itself, so a legacy func(*Context)
+// handler that calls c.Next() but has no way to return its result still
+// propagates it - see toHandlerFuncE.
+func (c *Context) Next() error {
+	c.hi++
+	if c.hi > len(c.handlers) {
+		return nil
+	}
+	err := c.handlers[c.hi-1](c)
+	c.nextErr = err
+	return err
+}
+
+// Baa returns the application c was created by, for middleware that
+// needs access to its Logger, DI container, etc.
+func (c *Context) Baa() *Baa {
+	return c.baa
+}
+
+// Context returns the request-scoped context.Context. It is cancelled
+// when the client connection closes or, for in-flight requests, when the
+// Baa application is shut down via Shutdown - handlers can select on
+// c.Context().Done() to abort long-running work.
+func (c *Context) Context() context.Context {
+	return c.ctx
+}
+
+// Param returns the value of the named route parameter matched for this
+// request, e.g. the "id" in "/user/:id", or "" if there is none.
+func (c *Context) Param(name string) string {
+	for _, p := range c.params {
+		if p.Key == name {
+			return p.Value
+		}
+	}
+	return ""
+}
+
+// SetParam records the value of a matched route parameter. It is called
+// by the router while matching a request and is not usually called by
+// application code.
+func (c *Context) SetParam(name, value string) {
+	c.params = append(c.params, Param{Key: name, Value: value})
+}
+
+// Set stores a value on the context under key.
+func (c *Context) Set(key string, v interface{}) {
+	if c.store == nil {
+		c.store = make(map[string]interface{})
+	}
+	c.store[key] = v
+}
+
+// Get retrieves a value previously stored with Set, or nil.
+func (c *Context) Get(key string) interface{} {
+	return c.store[key]
+}
@@ -0,0 +1,80 @@
+package baa
+
+// Group is a set of routes sharing a path prefix and a middleware stack.
+// Groups nest: a child group inherits its parent's prefix and middleware
+// and may add its own on top, without affecting routes registered
+// outside the group.
+type Group struct {
+	prefix     string
+	middleware []HandlerFuncE
+	baa        *Baa
+}
+
+// Group registers a route group under prefix, with middleware running
+// ahead of every route registered on the group (or its descendants).
+func (b *Baa) Group(prefix string, middleware ...Handler) *Group {
+	return &Group{prefix: prefix, middleware: toHandlerFuncEs(middleware), baa: b}
+}
+
+// Group creates a nested group under g, inheriting g's prefix and
+// middleware and adding its own on top.
+func (g *Group) Group(prefix string, middleware ...Handler) *Group {
+	return &Group{
+		prefix:     g.prefix + prefix,
+		middleware: combineHandlers(g.middleware, toHandlerFuncEs(middleware)),
+		baa:        g.baa,
+	}
+}
+
+// Use appends middleware to g's stack. It applies to every route
+// registered on g, or on a descendant group created from g, from this
+// point on, not to routes registered earlier or to other groups. A
+// descendant group created before the call keeps the middleware it
+// copied from g at creation and does not get the new middleware.
+func (g *Group) Use(h ...Handler) {
+	g.middleware = append(g.middleware, toHandlerFuncEs(h)...)
+}
+
+func combineHandlers(parent, h []HandlerFuncE) []HandlerFuncE {
+	combined := make([]HandlerFuncE, 0, len(parent)+len(h))
+	combined = append(combined, parent...)
+	combined = append(combined, h...)
+	return combined
+}
+
+func (g *Group) add(method, pattern string, h []Handler) *Route {
+	return g.baa.router.Add(method, g.prefix+pattern, combineHandlers(g.middleware, toHandlerFuncEs(h)))
+}
+
+// Get is a shortcut for g.add("GET", pattern, handlers)
+func (g *Group) Get(pattern string, h ...Handler) *Route {
+	rs := g.add("GET", pattern, h)
+	if g.baa.router.AutoHead() {
+		g.add("HEAD", pattern, h)
+	}
+	return rs
+}
+
+// Post is a shortcut for g.add("POST", pattern, handlers)
+func (g *Group) Post(pattern string, h ...Handler) *Route {
+	return g.add("POST", pattern, h)
+}
+
+// Put is a shortcut for g.add("PUT", pattern, handlers)
+func (g *Group) Put(pattern string, h ...Handler) *Route {
+	return g.add("PUT", pattern, h)
+}
+
+// Delete is a shortcut for g.add("DELETE", pattern, handlers)
+func (g *Group) Delete(pattern string, h ...Handler) *Route {
+	return g.add("DELETE", pattern, h)
+}
+
+// Any registers pattern on g for every HTTP method.
+func (g *Group) Any(pattern string, h ...Handler) *Route {
+	var rs *Route
+	for _, m := range g.baa.router.Methods() {
+		rs = g.add(m, pattern, h)
+	}
+	return rs
+}
@@ -0,0 +1,102 @@
+package baa
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func trailMiddleware(trail *[]string, name string) HandlerFunc {
+	return func(c *Context) {
+		*trail = append(*trail, name)
+		c.Next()
+	}
+}
+
+func equalTrail(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// TestGroupMiddlewareInheritance verifies a nested group runs its
+// parent's middleware, then its own, ahead of the route handler.
+func TestGroupMiddlewareInheritance(t *testing.T) {
+	app := New()
+	var trail []string
+
+	api := app.Group("/api", trailMiddleware(&trail, "api"))
+	v1 := api.Group("/v1", trailMiddleware(&trail, "v1"))
+	v1.Get("/ping", func(c *Context) {
+		trail = append(trail, "handler")
+	})
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/v1/ping", nil))
+
+	want := []string{"api", "v1", "handler"}
+	if !equalTrail(trail, want) {
+		t.Errorf("trail = %v, want %v", trail, want)
+	}
+}
+
+// TestGroupUseAppliesForwardOnly verifies g.Use only affects routes
+// registered on g after the call, not ones registered earlier.
+func TestGroupUseAppliesForwardOnly(t *testing.T) {
+	app := New()
+	var trail []string
+
+	g := app.Group("/g")
+	g.Get("/before", func(c *Context) {})
+	g.Use(trailMiddleware(&trail, "mw"))
+	g.Get("/after", func(c *Context) {})
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/g/before", nil))
+	if len(trail) != 0 {
+		t.Fatalf("trail = %v after /before, want empty", trail)
+	}
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/g/after", nil))
+	if !equalTrail(trail, []string{"mw"}) {
+		t.Errorf("trail = %v after /after, want [mw]", trail)
+	}
+}
+
+// TestGroupSiblingIsolation verifies middleware on one group does not
+// leak onto a sibling group sharing the same parent.
+func TestGroupSiblingIsolation(t *testing.T) {
+	app := New()
+	var trail []string
+
+	parent := app.Group("/p")
+	g1 := parent.Group("/g1", trailMiddleware(&trail, "g1"))
+	g2 := parent.Group("/g2")
+	g1.Get("/x", func(c *Context) {})
+	g2.Get("/y", func(c *Context) {})
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/p/g2/y", nil))
+	if len(trail) != 0 {
+		t.Errorf("trail = %v, want empty: g1's middleware ran for a g2 route", trail)
+	}
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/p/g1/x", nil))
+	if !equalTrail(trail, []string{"g1"}) {
+		t.Errorf("trail = %v, want [g1]", trail)
+	}
+}
+
+// TestGroupURLFor verifies a named route registered through a group
+// resolves with its full, prefixed pattern.
+func TestGroupURLFor(t *testing.T) {
+	app := New()
+	api := app.Group("/api")
+	api.Get("/users/:id", func(c *Context) {}).Name("user")
+
+	if got, want := app.URLFor("user", 42), "/api/users/42"; got != want {
+		t.Errorf("URLFor = %q, want %q", got, want)
+	}
+}
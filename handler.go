@@ -0,0 +1,40 @@
+package baa
+
+// toHandlerFuncE adapts h to HandlerFuncE, so route registration can mix
+// plain, error-less handlers with ones that participate in centralized
+// error handling. A plain handler (HandlerFunc or func(*Context)) has no
+// way to return a value, so if it wraps the rest of the chain by calling
+// c.Next() itself, the shim recovers that call's error off c (see
+// Context.Next) rather than silently discarding it; a handler that never
+// calls c.Next() still correctly produces a nil error.
+func toHandlerFuncE(h Handler) HandlerFuncE {
+	switch h := h.(type) {
+	case HandlerFuncE:
+		return h
+	case func(*Context) error:
+		return h
+	case HandlerFunc:
+		return func(c *Context) error {
+			c.nextErr = nil
+			h(c)
+			return c.nextErr
+		}
+	case func(*Context):
+		return func(c *Context) error {
+			c.nextErr = nil
+			h(c)
+			return c.nextErr
+		}
+	default:
+		panic("baa: unknown handler type")
+	}
+}
+
+// toHandlerFuncEs adapts a slice of Handler to HandlerFuncE, in order.
+func toHandlerFuncEs(h []Handler) []HandlerFuncE {
+	hs := make([]HandlerFuncE, len(h))
+	for i := range h {
+		hs[i] = toHandlerFuncE(h[i])
+	}
+	return hs
+}
@@ -0,0 +1,61 @@
+package baa
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestLegacyHandlerPropagatesNextError reproduces the scenario where a
+// legacy func(*Context) handler wraps the rest of the chain by calling
+// c.Next() itself and ignoring what it returns - here a Pre middleware
+// that passes every request through. The resulting error must still
+// reach the application's ErrorHandleFunc.
+func TestLegacyHandlerPropagatesNextError(t *testing.T) {
+	app := New()
+	var gotErr error
+	app.SetError(func(err error, c *Context) {
+		gotErr = err
+	})
+	app.Pre(HandlerFunc(func(c *Context) {
+		c.Next()
+	}))
+	wantErr := errors.New("boom")
+	app.Get("/boom", func(c *Context) error {
+		return wantErr
+	})
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/boom", nil))
+	if gotErr != wantErr {
+		t.Errorf("ErrorHandleFunc got %v, want %v", gotErr, wantErr)
+	}
+}
+
+// TestLegacyHandlerNoNextIsNilError verifies a legacy handler that never
+// calls c.Next() (i.e. it fully handles the request itself) still yields
+// a nil error, rather than leaking a stale error from an earlier request
+// sharing the pooled *Context.
+func TestLegacyHandlerNoNextIsNilError(t *testing.T) {
+	app := New()
+	errorCalled := false
+	app.SetError(func(err error, c *Context) {
+		errorCalled = true
+	})
+	app.Get("/boom", func(c *Context) error {
+		return errors.New("boom")
+	})
+	app.Get("/ok", func(c *Context) {
+		c.Resp.Write([]byte("ok"))
+	})
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/boom", nil))
+	if !errorCalled {
+		t.Fatal("ErrorHandleFunc was not called for /boom; test setup is broken")
+	}
+
+	errorCalled = false
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/ok", nil))
+	if errorCalled {
+		t.Error("ErrorHandleFunc called for /ok, want it not to run")
+	}
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/anjieych/baa"
+)
+
+// CORS answers cross-origin requests for the given origins (or any
+// origin, if none are given), including short-circuiting CORS preflight
+// OPTIONS requests with a 204.
+func CORS(origins ...string) baa.HandlerFuncE {
+	return func(c *baa.Context) error {
+		h := c.Resp.Header()
+		if len(origins) == 0 {
+			h.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			h.Add("Vary", "Origin")
+			origin := c.Req.Header.Get("Origin")
+			for _, o := range origins {
+				if o == origin {
+					h.Set("Access-Control-Allow-Origin", origin)
+					break
+				}
+			}
+		}
+		h.Set("Access-Control-Allow-Methods", "GET,POST,PUT,DELETE,PATCH,OPTIONS")
+		h.Set("Access-Control-Allow-Headers", "Content-Type,Authorization")
+		if c.Req.Method == http.MethodOptions {
+			c.Resp.WriteHeader(http.StatusNoContent)
+			return nil
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/anjieych/baa"
+)
+
+// gzipResponseWriter substitutes its Write for one that compresses
+// through w, while leaving header/status handling to the wrapped
+// http.ResponseWriter.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	w io.Writer
+}
+
+func (g *gzipResponseWriter) Write(b []byte) (int, error) {
+	return g.w.Write(b)
+}
+
+// Gzip compresses the response body with gzip when the client advertises
+// support for it via Accept-Encoding, and is a no-op otherwise.
+func Gzip() baa.HandlerFuncE {
+	return func(c *baa.Context) error {
+		if !strings.Contains(c.Req.Header.Get("Accept-Encoding"), "gzip") {
+			return c.Next()
+		}
+
+		c.Resp.Header().Set("Content-Encoding", "gzip")
+		c.Resp.Header().Add("Vary", "Accept-Encoding")
+
+		gz := gzip.NewWriter(c.Resp.ResponseWriter)
+		defer gz.Close()
+
+		orig := c.Resp.ResponseWriter
+		c.Resp.ResponseWriter = &gzipResponseWriter{ResponseWriter: orig, w: gz}
+		defer func() { c.Resp.ResponseWriter = orig }()
+
+		return c.Next()
+	}
+}
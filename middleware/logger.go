@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/anjieych/baa"
+)
+
+// Logger logs each request's method, path, response status, size and
+// latency via c.Baa().Logger(), once the rest of the chain has run.
+func Logger() baa.HandlerFuncE {
+	return func(c *baa.Context) error {
+		start := time.Now()
+		err := c.Next()
+		c.Baa().Logger().Printf("%s %s %d %d %s", c.Req.Method, c.Req.URL.Path,
+			c.Resp.Status(), c.Resp.Size(), time.Since(start))
+		return err
+	}
+}
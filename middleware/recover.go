@@ -0,0 +1,30 @@
+// Package middleware provides HandlerFuncE middleware for baa
+// applications: Recover, Logger, Gzip and CORS.
+package middleware
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	"github.com/anjieych/baa"
+)
+
+// Recover turns a panic anywhere downstream in the handler chain into an
+// error, so it reaches the application's ErrorHandleFunc instead of
+// crashing the process. The panic value and a stack trace are logged via
+// c.Baa().Logger() before the error is returned.
+func Recover() baa.HandlerFuncE {
+	return func(c *baa.Context) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				if e, ok := r.(error); ok {
+					err = e
+				} else {
+					err = fmt.Errorf("%v", r)
+				}
+				c.Baa().Logger().Printf("[Recover] %v\n%s", r, debug.Stack())
+			}
+		}()
+		return c.Next()
+	}
+}
@@ -0,0 +1,50 @@
+package baa
+
+import "strings"
+
+// mount records a sub-application mounted under a path prefix.
+type mount struct {
+	prefix string
+	sub    *Baa
+}
+
+// Mount registers sub's entire route tree under prefix: every request
+// whose path starts with prefix is dispatched to sub, with prefix
+// stripped from the path first. The parent's own middleware (registered
+// via Use) runs before the request reaches sub, but routing, NotFound,
+// Error handling, URLFor and DI all remain scoped to sub - it behaves
+// exactly as if it were serving the request directly on its own. This is
+// the natural way to compose several Baa applications (admin, api,
+// public, ...) behind one listener.
+func (b *Baa) Mount(prefix string, sub *Baa) {
+	prefix = strings.TrimRight(prefix, "/")
+	b.mounts = append(b.mounts, &mount{prefix: prefix, sub: sub})
+}
+
+// matchMount returns the mount (if any) registered for path, along with
+// path rewritten relative to that mount's prefix.
+func (b *Baa) matchMount(path string) (*mount, string) {
+	for _, m := range b.mounts {
+		if path == m.prefix {
+			return m, "/"
+		}
+		if strings.HasPrefix(path, m.prefix+"/") {
+			return m, path[len(m.prefix):]
+		}
+	}
+	return nil, path
+}
+
+// mountHandler builds the handler that hands the request off to m.sub
+// with its URL path rewritten to rel. Errors from within the sub-app are
+// already resolved by its own Error handler, so this always returns nil.
+func mountHandler(m *mount, rel string) HandlerFuncE {
+	return func(c *Context) error {
+		r2 := c.Req.Clone(c.Req.Context())
+		u := *c.Req.URL
+		u.Path = rel
+		r2.URL = &u
+		m.sub.ServeHTTP(c.Resp, r2)
+		return nil
+	}
+}
@@ -0,0 +1,121 @@
+package baa
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestMountDispatch verifies requests under the mount prefix reach the
+// sub-application with the prefix stripped from the path.
+func TestMountDispatch(t *testing.T) {
+	root := New()
+	admin := New()
+	admin.Get("/ping", func(c *Context) {
+		c.Resp.Write([]byte("pong"))
+	})
+	root.Mount("/admin", admin)
+
+	w := httptest.NewRecorder()
+	root.ServeHTTP(w, httptest.NewRequest("GET", "/admin/ping", nil))
+	if w.Body.String() != "pong" {
+		t.Errorf("body = %q, want %q", w.Body.String(), "pong")
+	}
+}
+
+// TestMountExactPrefix verifies a request that matches the mount prefix
+// exactly (no trailing segment) is routed to the sub-app's "/".
+func TestMountExactPrefix(t *testing.T) {
+	root := New()
+	admin := New()
+	admin.Get("/", func(c *Context) {
+		c.Resp.Write([]byte("root"))
+	})
+	root.Mount("/admin", admin)
+
+	w := httptest.NewRecorder()
+	root.ServeHTTP(w, httptest.NewRequest("GET", "/admin", nil))
+	if w.Body.String() != "root" {
+		t.Errorf("body = %q, want %q", w.Body.String(), "root")
+	}
+}
+
+// TestMountParentMiddlewareRunsFirst verifies the parent's own
+// middleware runs ahead of a mounted sub-app.
+func TestMountParentMiddlewareRunsFirst(t *testing.T) {
+	root := New()
+	admin := New()
+	var trail []string
+	root.Use(HandlerFunc(func(c *Context) {
+		trail = append(trail, "parent")
+		c.Next()
+	}))
+	admin.Get("/ping", func(c *Context) {
+		trail = append(trail, "sub")
+	})
+	root.Mount("/admin", admin)
+
+	root.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/admin/ping", nil))
+	if !equalTrail(trail, []string{"parent", "sub"}) {
+		t.Errorf("trail = %v, want [parent sub]", trail)
+	}
+}
+
+// TestMountNotFoundScoped verifies an unmatched path under the mount
+// prefix uses the sub-app's own NotFound handler, not the parent's.
+func TestMountNotFoundScoped(t *testing.T) {
+	root := New()
+	admin := New()
+	admin.SetNotFound(func(c *Context) {
+		c.Resp.WriteHeader(599)
+	})
+	root.Mount("/admin", admin)
+
+	w := httptest.NewRecorder()
+	root.ServeHTTP(w, httptest.NewRequest("GET", "/admin/missing", nil))
+	if w.Code != 599 {
+		t.Errorf("status = %d, want 599 (sub-app's NotFound)", w.Code)
+	}
+}
+
+// TestMountErrorScoped verifies an error returned by a sub-app's route
+// reaches the sub-app's own ErrorHandleFunc, not the parent's.
+func TestMountErrorScoped(t *testing.T) {
+	root := New()
+	admin := New()
+	rootErrorCalled := false
+	root.SetError(func(err error, c *Context) {
+		rootErrorCalled = true
+	})
+	subErr := errors.New("boom")
+	admin.SetError(func(err error, c *Context) {
+		if err != subErr {
+			t.Errorf("sub error handler got %v, want %v", err, subErr)
+		}
+		c.Resp.WriteHeader(555)
+	})
+	admin.Get("/ping", func(c *Context) error {
+		return subErr
+	})
+	root.Mount("/admin", admin)
+
+	w := httptest.NewRecorder()
+	root.ServeHTTP(w, httptest.NewRequest("GET", "/admin/ping", nil))
+	if w.Code != 555 {
+		t.Errorf("status = %d, want 555", w.Code)
+	}
+	if rootErrorCalled {
+		t.Error("parent's ErrorHandleFunc was called; want only the sub-app's")
+	}
+}
+
+// TestMountURLForScoped verifies URLFor on the sub-app resolves its own
+// named routes independently of the parent.
+func TestMountURLForScoped(t *testing.T) {
+	admin := New()
+	admin.Get("/users/:id", func(c *Context) {}).Name("user")
+
+	if got, want := admin.URLFor("user", 7), "/users/7"; got != want {
+		t.Errorf("URLFor = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,71 @@
+package baa
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Router matches an incoming request to a registered handler chain and
+// builds URLs for named routes. baa ships two implementations: the
+// original, simple mapRouter (see router_map.go), and routerRadix (see
+// router_radix.go), a radix tree geared towards large route tables. Use
+// Baa.SetRouter to swap the default, e.g. for NewRouterRadix(). A Route
+// keeps its handler chain in unexported fields, so a Router implemented
+// outside package baa cannot return a Route that dispatches a request.
+type Router interface {
+	// Add registers a handler chain for method and pattern, returning
+	// the created Route.
+	Add(method, pattern string, h []HandlerFuncE) *Route
+	// Match finds the Route registered for method and path, recording
+	// any matched route parameters on c. It returns nil if no route
+	// matches.
+	Match(method, path string, c *Context) *Route
+	// URLFor builds the URL for the named route, substituting args for
+	// its parameters in order.
+	URLFor(name string, args ...interface{}) string
+	// Methods lists the HTTP methods the router dispatches.
+	Methods() []string
+	// AutoHead reports whether a HEAD route is added automatically for
+	// every GET route.
+	AutoHead() bool
+	// SetAutoHead sets the AutoHead behaviour.
+	SetAutoHead(bool)
+	// AutoTrailingSlash reports whether a trailing slash is optional
+	// when matching.
+	AutoTrailingSlash() bool
+	// SetAutoTrailingSlash sets the AutoTrailingSlash behaviour.
+	SetAutoTrailingSlash(bool)
+}
+
+// Route represents a registered route, as returned by Baa.Get, Baa.Post,
+// etc. Its zero value is never useful to callers; always obtained from a
+// Router.
+type Route struct {
+	name     string
+	pattern  string
+	method   string
+	handlers []HandlerFuncE
+}
+
+// Name sets the route's name, so it can later be resolved with
+// Baa.URLFor, and returns the route for chaining.
+func (r *Route) Name(name string) *Route {
+	r.name = name
+	return r
+}
+
+// toURLArg renders a URLFor argument as a path segment.
+func toURLArg(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,123 @@
+package baa
+
+import (
+	"net/http"
+	"strings"
+)
+
+// mapRouter is baa's original router: routes are kept as a flat,
+// per-method list and matched by walking the list and comparing pattern
+// segments one by one. It is simple and predictable but O(routes) per
+// request; routerRadix trades a larger tree for much better behaviour on
+// big route tables.
+type mapRouter struct {
+	routes            map[string][]*Route
+	autoHead          bool
+	autoTrailingSlash bool
+}
+
+func newRouter() Router {
+	return &mapRouter{routes: make(map[string][]*Route)}
+}
+
+func (r *mapRouter) Add(method, pattern string, h []HandlerFuncE) *Route {
+	route := &Route{method: method, pattern: pattern, handlers: h}
+	r.routes[method] = append(r.routes[method], route)
+	return route
+}
+
+func (r *mapRouter) Match(method, path string, c *Context) *Route {
+	for _, route := range r.routes[method] {
+		if params, ok := matchPattern(route.pattern, path, r.autoTrailingSlash); ok {
+			for _, p := range params {
+				c.SetParam(p.Key, p.Value)
+			}
+			return route
+		}
+	}
+	return nil
+}
+
+// matchPattern matches a "/foo/:id/*rest"-style pattern against path,
+// returning the captured parameters.
+func matchPattern(pattern, path string, autoTrailingSlash bool) ([]Param, bool) {
+	if autoTrailingSlash {
+		path = strings.TrimSuffix(path, "/")
+		pattern = strings.TrimSuffix(pattern, "/")
+	} else if len(path) > 1 && strings.HasSuffix(path, "/") != strings.HasSuffix(pattern, "/") {
+		return nil, false
+	}
+	segs := splitPath(pattern)
+	parts := splitPath(path)
+	var params []Param
+	for i, seg := range segs {
+		switch {
+		case strings.HasPrefix(seg, "*"):
+			params = append(params, Param{Key: seg[1:], Value: strings.Join(parts[min(i, len(parts)):], "/")})
+			return params, true
+		case i >= len(parts):
+			return nil, false
+		case strings.HasPrefix(seg, ":"):
+			params = append(params, Param{Key: seg[1:], Value: parts[i]})
+		case seg != parts[i]:
+			return nil, false
+		}
+	}
+	if len(segs) != len(parts) {
+		return nil, false
+	}
+	return params, true
+}
+
+func splitPath(p string) []string {
+	p = strings.Trim(p, "/")
+	if p == "" {
+		return nil
+	}
+	return strings.Split(p, "/")
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func (r *mapRouter) URLFor(name string, args ...interface{}) string {
+	for _, routes := range r.routes {
+		for _, route := range routes {
+			if route.name == name {
+				return buildURL(route.pattern, args)
+			}
+		}
+	}
+	return ""
+}
+
+func buildURL(pattern string, args []interface{}) string {
+	segs := splitPath(pattern)
+	ai := 0
+	for i, seg := range segs {
+		if ai >= len(args) {
+			break
+		}
+		if strings.HasPrefix(seg, ":") || strings.HasPrefix(seg, "*") {
+			segs[i] = toURLArg(args[ai])
+			ai++
+		}
+	}
+	return "/" + strings.Join(segs, "/")
+}
+
+func (r *mapRouter) Methods() []string {
+	return []string{
+		http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete,
+		http.MethodPatch, http.MethodHead, http.MethodOptions,
+	}
+}
+
+func (r *mapRouter) AutoHead() bool              { return r.autoHead }
+func (r *mapRouter) SetAutoHead(v bool)          { r.autoHead = v }
+func (r *mapRouter) AutoTrailingSlash() bool     { return r.autoTrailingSlash }
+func (r *mapRouter) SetAutoTrailingSlash(v bool) { r.autoTrailingSlash = v }
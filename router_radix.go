@@ -0,0 +1,179 @@
+package baa
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+type radixNodeType uint8
+
+const (
+	radixStatic radixNodeType = iota
+	radixParam
+	radixCatchAll
+)
+
+// radixNode is one segment of a registered pattern. Children are kept
+// priority-sorted (static before param before catch-all) so that, at
+// matching time, a literal segment always wins over a wildcard at the
+// same depth.
+type radixNode struct {
+	path      string
+	paramName string
+	nType     radixNodeType
+	children  []*radixNode
+	route     *Route
+}
+
+func (n *radixNode) child(seg string) *radixNode {
+	for _, c := range n.children {
+		if c.path == seg {
+			return c
+		}
+	}
+	child := &radixNode{path: seg}
+	switch {
+	case strings.HasPrefix(seg, ":"):
+		child.nType = radixParam
+		child.paramName = seg[1:]
+	case strings.HasPrefix(seg, "*"):
+		child.nType = radixCatchAll
+		child.paramName = seg[1:]
+	default:
+		child.nType = radixStatic
+	}
+	n.children = append(n.children, child)
+	sort.SliceStable(n.children, func(i, j int) bool {
+		return n.children[i].nType < n.children[j].nType
+	})
+	return child
+}
+
+func (n *radixNode) insert(segs []string, route *Route) {
+	cur := n
+	for _, seg := range segs {
+		cur = cur.child(seg)
+	}
+	cur.route = route
+}
+
+// match walks path (already trimmed of leading/trailing slashes) against
+// the tree one segment at a time, appending captured parameters to params
+// (which the caller pre-sizes on *Context to avoid allocating). Unlike
+// splitPath, it never allocates a []string for the segments: each segment
+// is a substring of path, sliced off as matching proceeds.
+func (n *radixNode) match(path string, params *Params) *Route {
+	if path == "" {
+		return n.route
+	}
+	seg, rest := cutSegment(path)
+	for _, child := range n.children {
+		switch child.nType {
+		case radixStatic:
+			if child.path != seg {
+				continue
+			}
+			if route := child.match(rest, params); route != nil {
+				return route
+			}
+		case radixParam:
+			saved := len(*params)
+			*params = append(*params, Param{Key: child.paramName, Value: seg})
+			if route := child.match(rest, params); route != nil {
+				return route
+			}
+			*params = (*params)[:saved]
+		case radixCatchAll:
+			if child.route == nil {
+				continue
+			}
+			*params = append(*params, Param{Key: child.paramName, Value: path})
+			return child.route
+		}
+	}
+	return nil
+}
+
+// cutSegment splits path (no leading slash) at its first "/", returning
+// the first segment and the remainder, with no leading slash of its own.
+func cutSegment(path string) (seg, rest string) {
+	if i := strings.IndexByte(path, '/'); i >= 0 {
+		return path[:i], path[i+1:]
+	}
+	return path, ""
+}
+
+// routerRadix is a radix-tree Router: routes are stored per HTTP method
+// as a tree of nodes sharing common path prefixes (static, :param and
+// *catchAll children), giving matching roughly proportional to the depth
+// of the path rather than the number of registered routes. It is a
+// drop-in alternative to mapRouter; enable it with
+// Baa.SetRouter(NewRouterRadix()).
+type routerRadix struct {
+	trees             map[string]*radixNode
+	all               []*Route
+	autoHead          bool
+	autoTrailingSlash bool
+}
+
+// newRouterRadix creates a Router backed by a per-method radix tree.
+func newRouterRadix() Router {
+	return &routerRadix{trees: make(map[string]*radixNode)}
+}
+
+// NewRouterRadix returns a radix-tree Router, for use with Baa.SetRouter.
+func NewRouterRadix() Router {
+	return newRouterRadix()
+}
+
+func (r *routerRadix) Add(method, pattern string, h []HandlerFuncE) *Route {
+	route := &Route{method: method, pattern: pattern, handlers: h}
+	root := r.trees[method]
+	if root == nil {
+		root = &radixNode{}
+		r.trees[method] = root
+	}
+	root.insert(splitPath(route.pattern), route)
+	r.all = append(r.all, route)
+	return route
+}
+
+func (r *routerRadix) Match(method, path string, c *Context) *Route {
+	root := r.trees[method]
+	if root == nil {
+		return nil
+	}
+	if r.autoTrailingSlash {
+		path = strings.TrimSuffix(path, "/")
+	}
+	params := c.params
+	route := root.match(strings.Trim(path, "/"), &params)
+	if route != nil && !r.autoTrailingSlash && len(path) > 1 &&
+		strings.HasSuffix(path, "/") != strings.HasSuffix(route.pattern, "/") {
+		return nil
+	}
+	c.params = params
+	return route
+}
+
+func (r *routerRadix) URLFor(name string, args ...interface{}) string {
+	for _, route := range r.all {
+		if route.name == name {
+			return buildURL(route.pattern, args)
+		}
+	}
+	return ""
+}
+
+func (r *routerRadix) Methods() []string {
+	return []string{
+		http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete,
+		http.MethodPatch, http.MethodHead, http.MethodOptions,
+	}
+}
+
+func (r *routerRadix) AutoHead() bool              { return r.autoHead }
+func (r *routerRadix) SetAutoHead(v bool)          { r.autoHead = v }
+func (r *routerRadix) AutoTrailingSlash() bool     { return r.autoTrailingSlash }
+func (r *routerRadix) SetAutoTrailingSlash(v bool) { r.autoTrailingSlash = v }
@@ -0,0 +1,166 @@
+package baa
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func newRadixContext() *Context {
+	return newContext(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil), New())
+}
+
+// TestRouterRadixStaticBeatsParam verifies that a literal segment always
+// wins over a :param sibling at the same depth, regardless of
+// registration order.
+func TestRouterRadixStaticBeatsParam(t *testing.T) {
+	r := newRouterRadix()
+	r.Add("GET", "/users/:id", nil).Name("param")
+	r.Add("GET", "/users/admin", nil).Name("static")
+
+	c := newRadixContext()
+	route := r.Match("GET", "/users/admin", c)
+	if route == nil || route.name != "static" {
+		t.Fatalf("Match(/users/admin) = %v, want the static route", route)
+	}
+
+	c = newRadixContext()
+	route = r.Match("GET", "/users/42", c)
+	if route == nil || route.name != "param" {
+		t.Fatalf("Match(/users/42) = %v, want the param route", route)
+	}
+	if got := c.Param("id"); got != "42" {
+		t.Errorf("Param(id) = %q, want %q", got, "42")
+	}
+}
+
+// TestRouterRadixMultiParamOrder verifies that params from a multi-segment
+// pattern are captured in left-to-right (registration) order, not the
+// order the recursive matcher happens to unwind in.
+func TestRouterRadixMultiParamOrder(t *testing.T) {
+	r := newRouterRadix()
+	r.Add("GET", "/:a/:b/:c", nil)
+
+	c := newRadixContext()
+	route := r.Match("GET", "/1/2/3", c)
+	if route == nil {
+		t.Fatal("Match(/1/2/3) = nil, want a route")
+	}
+	want := Params{{Key: "a", Value: "1"}, {Key: "b", Value: "2"}, {Key: "c", Value: "3"}}
+	if len(c.params) != len(want) {
+		t.Fatalf("params = %+v, want %+v", c.params, want)
+	}
+	for i, p := range want {
+		if c.params[i] != p {
+			t.Errorf("params[%d] = %+v, want %+v", i, c.params[i], p)
+		}
+	}
+}
+
+// TestRouterRadixCatchAll verifies that a *catchAll only matches when
+// nothing more specific does, and captures the remaining path.
+func TestRouterRadixCatchAll(t *testing.T) {
+	r := newRouterRadix()
+	r.Add("GET", "/static/*file", nil).Name("catchall")
+	r.Add("GET", "/static/favicon.ico", nil).Name("static")
+
+	c := newRadixContext()
+	route := r.Match("GET", "/static/favicon.ico", c)
+	if route == nil || route.name != "static" {
+		t.Fatalf("Match(/static/favicon.ico) = %v, want the static route", route)
+	}
+
+	c = newRadixContext()
+	route = r.Match("GET", "/static/js/app.js", c)
+	if route == nil || route.name != "catchall" {
+		t.Fatalf("Match(/static/js/app.js) = %v, want the catchall route", route)
+	}
+	if got := c.Param("file"); got != "js/app.js" {
+		t.Errorf("Param(file) = %q, want %q", got, "js/app.js")
+	}
+}
+
+// TestRouterRadixNoMatch verifies that an unmatched method or path
+// returns nil rather than panicking or matching the wrong route.
+func TestRouterRadixNoMatch(t *testing.T) {
+	r := newRouterRadix()
+	r.Add("GET", "/users/:id", nil)
+
+	c := newRadixContext()
+	if route := r.Match("POST", "/users/42", c); route != nil {
+		t.Errorf("Match(POST) = %v, want nil", route)
+	}
+	c = newRadixContext()
+	if route := r.Match("GET", "/users", c); route != nil {
+		t.Errorf("Match(/users) = %v, want nil", route)
+	}
+}
+
+// TestRouterRadixURLFor verifies named routes registered on routerRadix
+// can be resolved back into a URL.
+func TestRouterRadixURLFor(t *testing.T) {
+	r := newRouterRadix()
+	r.Add("GET", "/users/:id/posts/:postID", nil).Name("user-post")
+
+	got := r.URLFor("user-post", 42, "hello")
+	want := "/users/42/posts/hello"
+	if got != want {
+		t.Errorf("URLFor = %q, want %q", got, want)
+	}
+}
+
+// TestRouterRadixMatchDoesNotAllocate locks in the claim made by
+// BenchmarkRouterRadixNestedGroups: once the Context's param slots are
+// warmed up, matching a request against routerRadix allocates nothing.
+func TestRouterRadixMatchDoesNotAllocate(t *testing.T) {
+	r := newRouterRadix()
+	r.Add("GET", "/users/:id", nil)
+
+	c := newRadixContext()
+	c.Req = httptest.NewRequest("GET", "/users/42", nil)
+	r.Match("GET", "/users/42", c) // warm c.params
+
+	avg := testing.AllocsPerRun(100, func() {
+		c.params = c.params[:0]
+		r.Match("GET", "/users/42", c)
+	})
+	if avg != 0 {
+		t.Errorf("Match allocated %v times per run, want 0", avg)
+	}
+}
+
+// BenchmarkRouterRadixNestedGroups exercises routerRadix under a deeply
+// nested group/middleware stack. Matching a request does not allocate
+// once the Context's param slots are warmed up: path segments are sliced
+// off the request path in place (see radixNode.match/cutSegment) rather
+// than split into a fresh []string. The allocations it reports cover all
+// of ServeHTTP, including the per-request context.Context, so they are
+// not zero; TestRouterRadixMatchDoesNotAllocate checks the match alone.
+func BenchmarkRouterRadixNestedGroups(b *testing.B) {
+	app := New()
+	app.SetRouter(newRouterRadix())
+
+	noop := func(c *Context) { c.Next() }
+
+	const depth = 10
+	g := app.Group("/g", noop)
+	for i := 1; i < depth; i++ {
+		g = g.Group("/g", noop)
+	}
+	g.Get("/:id", func(c *Context) {})
+
+	path := ""
+	for i := 0; i < depth; i++ {
+		path += "/g"
+	}
+	path += "/42"
+
+	req := httptest.NewRequest("GET", path, nil)
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req) // warm the context pool
+
+	b.ResetTimer()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		app.ServeHTTP(w, req)
+	}
+}